@@ -0,0 +1,67 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+
+	"github.com/ayang64/stutter/pkg/symindex"
+)
+
+// runIndex implements "stutter index [-db path] [-name substr] [-kind kind] <dirs...>".
+// It reuses the on-disk symbol index so repeated scans of the same tree
+// only reparse files that changed since the last run.
+func runIndex(args []string) {
+	fs := flag.NewFlagSet("index", flag.ExitOnError)
+	db := fs.String("db", ".stutter-index", "path to the on-disk symbol index")
+	name := fs.String("name", "", "only print symbols whose name contains this substring")
+	kind := fs.String("kind", "", "only print symbols of this kind (func, method, struct, interface, const, var, field)")
+	fs.Parse(args)
+
+	dirs := fs.Args()
+	if len(dirs) == 0 {
+		dirs = []string{"."}
+	}
+
+	idx, err := symindex.Load(*db)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for _, dir := range dirs {
+		reparsed, err := idx.Walk(dir)
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("%s: reparsed %d file(s)", dir, reparsed)
+	}
+
+	if err := idx.Save(*db); err != nil {
+		log.Fatal(err)
+	}
+
+	for _, sym := range idx.Query(*name, parseKind(*kind)) {
+		fmt.Printf("%s\t%s\t%s\t%s\n", sym.Position, sym.Kind, sym.Package, sym.Name)
+	}
+}
+
+func parseKind(s string) symindex.Kind {
+	switch s {
+	case "func":
+		return symindex.KindFunc
+	case "method":
+		return symindex.KindMethod
+	case "struct":
+		return symindex.KindStruct
+	case "interface":
+		return symindex.KindInterface
+	case "const":
+		return symindex.KindConst
+	case "var":
+		return symindex.KindVar
+	case "field":
+		return symindex.KindField
+	default:
+		return symindex.KindUnknown
+	}
+}
@@ -0,0 +1,22 @@
+// Command stutter runs the stutter analyzer as a standalone go vet-style
+// tool, so it can be invoked directly or wired into golangci-lint and
+// other golang.org/x/tools analysis drivers. It also provides an "index"
+// subcommand that builds or refreshes the on-disk symbol index consumed
+// by repeated scans over large monorepos.
+package main
+
+import (
+	"os"
+
+	"golang.org/x/tools/go/analysis/singlechecker"
+
+	"github.com/ayang64/stutter/pkg/stutteranalyzer"
+)
+
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "index" {
+		runIndex(os.Args[2:])
+		return
+	}
+	singlechecker.Main(stutteranalyzer.Analyzer)
+}
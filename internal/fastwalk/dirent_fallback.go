@@ -0,0 +1,17 @@
+//go:build !(linux || darwin || ios || freebsd || netbsd || openbsd)
+
+package fastwalk
+
+import (
+	"io/fs"
+	"os"
+)
+
+// readDir falls back to os.ReadDir on platforms without a verified-compatible
+// syscall.Dirent layout: non-unix platforms (windows, js/wasm, plan9, ...) as
+// well as the unix variants dirent_unix.go deliberately excludes (dragonfly,
+// solaris, illumos, aix). Types are resolved at readdir time by the os
+// package rather than lazily, since there's no cheaper path available here.
+func readDir(dir string) ([]fs.DirEntry, error) {
+	return os.ReadDir(dir)
+}
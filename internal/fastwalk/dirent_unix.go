@@ -0,0 +1,149 @@
+//go:build linux || darwin || ios || freebsd || netbsd || openbsd
+
+// The build list above is deliberately narrower than the "unix" tag: it's
+// every GOOS verified to share the syscall.Dirent layout (Reclen, Type,
+// Name) this file assumes. dragonfly, solaris, illumos, and aix are unix
+// but lay their Dirent out differently (or lack d_type entirely), so they
+// fall back to os.ReadDir in dirent_fallback.go instead.
+
+package fastwalk
+
+import (
+	"io/fs"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// dirEntry is a directory entry whose type comes straight from the raw
+// dirent d_type field, so readDir only calls lstat for the entries whose
+// d_type is not a directory, symlink, or regular file.
+type dirEntry struct {
+	dir  string
+	name string
+	typ  fs.FileMode // fs.ModeIrregular if neither d_type nor lstat classified it
+}
+
+func (d dirEntry) Name() string      { return d.name }
+func (d dirEntry) IsDir() bool       { return d.typ.IsDir() }
+func (d dirEntry) Type() fs.FileMode { return d.typ }
+
+func (d dirEntry) Info() (fs.FileInfo, error) {
+	return os.Lstat(d.dir + string(os.PathSeparator) + d.name)
+}
+
+// dtypeToFileMode maps a raw d_type byte from a Unix dirent to the
+// equivalent fs.FileMode bit, so callers can tell directories from
+// regular files without ever calling lstat.
+func dtypeToFileMode(dtype uint8) fs.FileMode {
+	switch dtype {
+	case syscall.DT_DIR:
+		return fs.ModeDir
+	case syscall.DT_LNK:
+		return fs.ModeSymlink
+	case syscall.DT_REG:
+		return 0
+	default:
+		// DT_UNKNOWN (some filesystems/platforms never fill d_type in) or
+		// a rarer type such as a fifo or socket: readDir falls back to
+		// lstat to find out.
+		return fs.ModeIrregular
+	}
+}
+
+// readDir reads the directory dir using raw getdents(2) via
+// syscall.ReadDirent, classifying each entry from its d_type byte instead
+// of calling lstat on every entry the way os.ReadDir / filepath.WalkDir do.
+func readDir(dir string) ([]fs.DirEntry, error) {
+	f, err := os.Open(dir)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []fs.DirEntry
+	buf := make([]byte, 64*1024)
+	for {
+		n, err := syscall.ReadDirent(int(f.Fd()), buf)
+		if err != nil {
+			return nil, &os.PathError{Op: "readdirent", Path: dir, Err: err}
+		}
+		if n <= 0 {
+			break
+		}
+
+		rest := buf[:n]
+		for len(rest) > 0 {
+			reclen, ok := direntReclen(rest)
+			if !ok || reclen > uint64(len(rest)) {
+				break
+			}
+			rec := rest[:reclen]
+			rest = rest[reclen:]
+
+			ino := direntIno(rec)
+			if ino == 0 {
+				continue // deleted/whiteout entry
+			}
+
+			name := direntName(rec)
+			if name == "." || name == ".." || name == "" {
+				continue
+			}
+
+			typ := dtypeToFileMode(direntType(rec))
+			if typ == fs.ModeIrregular {
+				if info, err := os.Lstat(dir + string(os.PathSeparator) + name); err == nil {
+					typ = info.Mode().Type()
+				}
+			}
+			entries = append(entries, dirEntry{
+				dir:  dir,
+				name: name,
+				typ:  typ,
+			})
+		}
+	}
+	return entries, nil
+}
+
+func direntIno(buf []byte) uint64 {
+	return *(*uint64)(unsafe.Pointer(&buf[0]))
+}
+
+func direntReclen(buf []byte) (uint64, bool) {
+	off := unsafe.Offsetof(syscall.Dirent{}.Reclen)
+	if len(buf) < int(off)+2 {
+		return 0, false
+	}
+	return uint64(*(*uint16)(unsafe.Pointer(&buf[off]))), true
+}
+
+func direntType(buf []byte) uint8 {
+	off := unsafe.Offsetof(syscall.Dirent{}.Type)
+	if len(buf) <= int(off) {
+		return 0 // DT_UNKNOWN
+	}
+	return buf[off]
+}
+
+func direntName(buf []byte) string {
+	nameOff := unsafe.Offsetof(syscall.Dirent{}.Name)
+	if len(buf) <= int(nameOff) {
+		return ""
+	}
+	name := buf[nameOff:]
+	if i := indexByte(name, 0); i >= 0 {
+		name = name[:i]
+	}
+	return string(name)
+}
+
+func indexByte(b []byte, c byte) int {
+	for i, x := range b {
+		if x == c {
+			return i
+		}
+	}
+	return -1
+}
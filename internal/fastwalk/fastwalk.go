@@ -0,0 +1,213 @@
+// Package fastwalk provides a faster version of filepath.WalkDir for
+// walking directory trees. It reads raw directory entries instead of
+// calling os.Lstat on every file the way filepath.WalkDir does, and
+// prefetches subdirectories with a bounded pool of worker goroutines so
+// the slow part -- the readdir syscall itself -- overlaps with the
+// caller's per-directory work instead of happening serially.
+//
+// Despite that concurrency, walkFn is always invoked in a single,
+// deterministic pre-order DFS over each directory's entries (sorted by
+// name): only the readdir syscalls run ahead of time in the background,
+// never the callback. Two runs over the same tree therefore report
+// findings in the same order.
+//
+// This is a deliberate, narrower scope than "dispatch callbacks to a
+// worker pool": an earlier version of this package ran one goroutine per
+// root and fed callbacks from a shared queue, which made output order
+// depend on goroutine scheduling. Making walkFn callbacks themselves
+// concurrent and output deterministic are mutually exclusive unless
+// results are buffered and replayed in a fixed order, which would delay
+// every callback until its whole subtree finished reading -- worse
+// latency than just reading ahead. Walk keeps the readdir-ahead half of
+// that tradeoff and drops the callback-concurrency half; roots are
+// visited one at a time, in the order given, though their readdir
+// syscalls are still prefetched across root boundaries (see Walk).
+package fastwalk
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+// ErrSkipFiles is returned by a walk function to indicate that all
+// remaining files in the current directory should be skipped, but
+// subdirectories should still be walked. It is distinct from fs.SkipDir,
+// which also skips subdirectories.
+var ErrSkipFiles = errors.New("fastwalk: skip remaining files in directory")
+
+// Config holds fastwalk configuration.
+type Config struct {
+	// NumWorkers is the number of directories to prefetch concurrently.
+	// A value <= 0 defaults to runtime.NumCPU().
+	NumWorkers int
+}
+
+// Walk walks the trees rooted at each of roots, in the order given, and
+// calls walkFn for every file and directory encountered. Within a single
+// directory, entries are always visited in sorted-by-name order, so the
+// full sequence of walkFn calls -- and therefore any output built from
+// it -- is deterministic across runs of the same tree.
+//
+// walkFn may return fs.SkipDir to skip the directory being visited and its
+// descendants, or ErrSkipFiles to skip the remaining files (not
+// subdirectories) of the directory currently being visited.
+//
+// Callback order across multiple roots is still strictly sequential --
+// every callback for roots[0] happens before the first callback for
+// roots[1] -- but each root's own top-level readdir is prefetched before
+// any root is visited, so with multiple roots their initial syscalls
+// overlap instead of happening one after another.
+func Walk(conf Config, roots []string, walkFn fs.WalkDirFunc) error {
+	numWorkers := conf.NumWorkers
+	if numWorkers <= 0 {
+		numWorkers = runtime.NumCPU()
+	}
+
+	w := &walker{
+		fn:      walkFn,
+		sem:     make(chan struct{}, numWorkers),
+		futures: map[string]*future{},
+	}
+
+	for _, root := range roots {
+		w.prefetch(root)
+	}
+
+	var firstErr error
+	for _, root := range roots {
+		if err := w.walkRoot(root); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// walker prefetches directory contents with a bounded pool of background
+// goroutines, but only ever calls fn from the single, deterministic DFS
+// driven by walkRoot/visit.
+type walker struct {
+	fn  fs.WalkDirFunc
+	sem chan struct{}
+
+	mu      sync.Mutex
+	futures map[string]*future
+}
+
+// future is the in-flight or completed result of reading one directory.
+type future struct {
+	done    chan struct{}
+	entries []fs.DirEntry
+	err     error
+}
+
+func (w *walker) walkRoot(root string) error {
+	info, err := os.Lstat(root)
+	if err != nil {
+		return w.fn(root, nil, err)
+	}
+
+	switch err := w.fn(root, fs.FileInfoToDirEntry(info), nil); err {
+	case nil:
+		if info.IsDir() {
+			w.prefetch(root)
+			return w.visit(root)
+		}
+		return nil
+	case fs.SkipDir:
+		return nil
+	default:
+		return err
+	}
+}
+
+// prefetch kicks off a background readDir(dir) call, deduplicated by
+// path, bounded to at most cap(w.sem) concurrent syscalls.
+func (w *walker) prefetch(dir string) {
+	w.mu.Lock()
+	if _, ok := w.futures[dir]; ok {
+		w.mu.Unlock()
+		return
+	}
+	f := &future{done: make(chan struct{})}
+	w.futures[dir] = f
+	w.mu.Unlock()
+
+	go func() {
+		w.sem <- struct{}{}
+		defer func() { <-w.sem }()
+
+		entries, err := readDir(dir)
+		if err == nil {
+			sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+		}
+		f.entries, f.err = entries, err
+		close(f.done)
+	}()
+}
+
+// fetch blocks until dir's prefetched entries are ready, triggering the
+// read itself if nothing prefetched it yet.
+func (w *walker) fetch(dir string) ([]fs.DirEntry, error) {
+	w.prefetch(dir)
+	w.mu.Lock()
+	f := w.futures[dir]
+	w.mu.Unlock()
+	<-f.done
+	return f.entries, f.err
+}
+
+// visit deterministically walks dir's entries in sorted-name order,
+// prefetching subdirectories' contents up front so their readDir calls
+// overlap with this directory's own file callbacks instead of blocking
+// the DFS serially.
+func (w *walker) visit(dir string) error {
+	entries, err := w.fetch(dir)
+	if err != nil {
+		return w.fn(dir, nil, err)
+	}
+
+	for _, ent := range entries {
+		if ent.IsDir() {
+			w.prefetch(filepath.Join(dir, ent.Name()))
+		}
+	}
+
+	skipFiles := false
+	for _, ent := range entries {
+		path := filepath.Join(dir, ent.Name())
+
+		if !ent.IsDir() {
+			if skipFiles {
+				continue
+			}
+			switch err := w.fn(path, ent, nil); err {
+			case nil:
+			case ErrSkipFiles:
+				skipFiles = true
+			case fs.SkipDir:
+				// skip the rest of the containing directory
+				return nil
+			default:
+				return err
+			}
+			continue
+		}
+
+		switch err := w.fn(path, ent, nil); err {
+		case nil:
+			if err := w.visit(path); err != nil {
+				return err
+			}
+		case fs.SkipDir:
+			// skip this subdirectory entirely
+		default:
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package fastwalk
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func mkTree(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for rel, content := range files {
+		path := filepath.Join(root, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func walkPaths(t *testing.T, root string, conf Config, walkFn fs.WalkDirFunc) []string {
+	t.Helper()
+	var got []string
+	err := Walk(conf, []string{root}, func(path string, d fs.DirEntry, err error) error {
+		rel, relErr := filepath.Rel(root, path)
+		if relErr != nil {
+			t.Fatal(relErr)
+		}
+		if rel != "." {
+			got = append(got, rel)
+		}
+		if walkFn != nil {
+			return walkFn(path, d, err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return got
+}
+
+func TestWalkVisitsEveryFileAndDir(t *testing.T) {
+	root := mkTree(t, map[string]string{
+		"a.go":            "package a",
+		"sub/b.go":        "package sub",
+		"sub/c.go":        "package sub",
+		"sub2/d.go":       "package sub2",
+		"sub/nested/e.go": "package nested",
+	})
+
+	got := walkPaths(t, root, Config{}, nil)
+
+	want := map[string]bool{
+		"a.go": true, "sub": true, "sub/b.go": true, "sub/c.go": true,
+		"sub2": true, "sub2/d.go": true, "sub/nested": true, "sub/nested/e.go": true,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d entries %v, want %d entries %v", len(got), got, len(want), want)
+	}
+	for _, g := range got {
+		if !want[g] {
+			t.Errorf("unexpected entry %q", g)
+		}
+	}
+}
+
+func TestWalkSkipDirPrunesSubtree(t *testing.T) {
+	root := mkTree(t, map[string]string{
+		"a.go":            "package a",
+		"vendor/b.go":     "package vendor",
+		"vendor/sub/c.go": "package sub",
+	})
+
+	got := walkPaths(t, root, Config{}, func(path string, d fs.DirEntry, err error) error {
+		if d.IsDir() && d.Name() == "vendor" {
+			return fs.SkipDir
+		}
+		return nil
+	})
+
+	// The vendor directory entry itself is still reported (matching
+	// fs.SkipDir's usual meaning), but none of its contents should be.
+	for _, g := range got {
+		if filepath.Dir(g) == "vendor" || g == "vendor/sub" {
+			t.Errorf("entry %q should have been pruned by SkipDir", g)
+		}
+	}
+	want := map[string]bool{"a.go": true, "vendor": true}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want entries %v", got, want)
+	}
+	for _, g := range got {
+		if !want[g] {
+			t.Errorf("unexpected entry %q", g)
+		}
+	}
+}
+
+func TestWalkErrSkipFilesStillDescends(t *testing.T) {
+	root := mkTree(t, map[string]string{
+		"dir/a.go":     "package dir",
+		"dir/b.go":     "package dir",
+		"dir/sub/c.go": "package sub",
+	})
+
+	seenFileInDir := map[string]bool{} // parent dir -> already returned ErrSkipFiles there
+	got := walkPaths(t, root, Config{}, func(path string, d fs.DirEntry, err error) error {
+		if !d.IsDir() {
+			parent := filepath.Dir(path)
+			if seenFileInDir[parent] {
+				t.Errorf("file %q visited after ErrSkipFiles in %q", path, parent)
+			}
+			seenFileInDir[parent] = true
+			return ErrSkipFiles
+		}
+		return nil
+	})
+
+	var sawSecondFileInDir, sawSubdirFile bool
+	for _, g := range got {
+		switch g {
+		case "dir/b.go":
+			sawSecondFileInDir = true
+		case "dir/sub/c.go":
+			sawSubdirFile = true
+		}
+	}
+	if sawSecondFileInDir {
+		t.Errorf("dir/b.go should have been skipped by ErrSkipFiles, got %v", got)
+	}
+	if !sawSubdirFile {
+		t.Errorf("expected dir/sub/c.go to still be visited, got %v", got)
+	}
+}
+
+func TestWalkOutputIsDeterministic(t *testing.T) {
+	root := mkTree(t, map[string]string{
+		"z/a.go": "package z",
+		"m/a.go": "package m",
+		"a/a.go": "package a",
+		"top.go": "package top",
+	})
+
+	first := walkPaths(t, root, Config{NumWorkers: 8}, nil)
+	for i := 0; i < 10; i++ {
+		got := walkPaths(t, root, Config{NumWorkers: 8}, nil)
+		if len(got) != len(first) {
+			t.Fatalf("run %d: got %d entries, want %d", i, len(got), len(first))
+		}
+		for j := range got {
+			if got[j] != first[j] {
+				t.Fatalf("run %d: order differs at %d: got %q, want %q", i, j, got[j], first[j])
+			}
+		}
+	}
+}
@@ -1,18 +1,21 @@
 package main
 
 import (
-	"fmt"
+	"flag"
 	"go/ast"
 	"go/parser"
 	"go/token"
 	"io/fs"
 	"log"
 	"os"
-	"path/filepath"
-	"runtime"
 	"strings"
 	"sync"
 	"unicode/utf8"
+
+	"github.com/ayang64/stutter/internal/fastwalk"
+	"github.com/ayang64/stutter/pkg/report"
+	"github.com/ayang64/stutter/pkg/safeparse"
+	"github.com/ayang64/stutter/pkg/stutteranalyzer"
 )
 
 type Symlen struct {
@@ -39,124 +42,123 @@ func (s *Symlen) Accumulate(n string, pos token.Position) {
 	}
 }
 
-type Stutter struct {
-	Symbol   string
-	Package  string
-	Position token.Position
-}
+func (s *Symlen) Stats() report.Stats {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 
-func suggest(p string, s string) string {
-	if idx := strings.Index(strings.ToLower(s), strings.ToLower(p)); idx != -1 {
-		s = s[:idx] + s[idx+len(p):]
+	return report.Stats{
+		NumSymbols:      s.NumSymbols,
+		TotalLength:     s.TotalLength,
+		LongestName:     s.LongestName,
+		Longest:         s.Longest,
+		LongestPosition: s.LongestPosition,
 	}
-	return p + "." + s
-}
-
-func (s Stutter) String() string {
-	return fmt.Sprintf("%s: consider changing %q to %q", s.Position, s.Package+"."+s.Symbol, suggest(s.Package, s.Symbol))
 }
 
-type Visit struct {
-	symlen  *Symlen
-	Stutter []Stutter
-	Package string
-	Fset    *token.FileSet
-}
-
-func (v *Visit) Append(symb string, pkg string, pos token.Position) {
-	v.Stutter = append(v.Stutter, Stutter{
-		Symbol:   symb,
-		Package:  pkg,
-		Position: pos,
+// accumulateSymlen feeds every declared symbol name in file into symlen's
+// running stats, independent of whether it stutters. This bookkeeping
+// isn't part of the stutter heuristic itself, so it stays here rather
+// than in pkg/stutteranalyzer: the analyzer only knows about the
+// identifiers it reports on, not the full-corpus average/longest-name
+// stats this binary also prints.
+func accumulateSymlen(fset *token.FileSet, symlen *Symlen, file *ast.File) {
+	ast.Inspect(file, func(node ast.Node) bool {
+		switch d := node.(type) {
+		case *ast.FuncDecl:
+			symlen.Accumulate(d.Name.String(), fset.PositionFor(d.Pos(), true))
+		case *ast.TypeSpec:
+			symlen.Accumulate(d.Name.String(), fset.PositionFor(d.Pos(), true))
+		case *ast.ValueSpec:
+			for _, name := range d.Names {
+				symlen.Accumulate(name.String(), fset.PositionFor(d.Pos(), true))
+			}
+		}
+		return true
 	})
 }
 
-func (s *Visit) Visit(node ast.Node) ast.Visitor {
-	// case insensitive string contains function.
-	contains := func(a, b string) bool {
-		return strings.Contains(strings.ToLower(a), strings.ToLower(b))
-	}
-	switch v := node.(type) {
-	case *ast.FuncDecl:
-		s.symlen.Accumulate(v.Name.String(), s.Fset.PositionFor(v.Pos(), true))
-		if v.Recv == nil && v.Name.IsExported() && contains(v.Name.String(), s.Package) {
-			s.Append(v.Name.String(), s.Package, s.Fset.PositionFor(v.Pos(), true))
-		}
-	case *ast.GenDecl:
-		for _, spec := range v.Specs {
-			switch d := spec.(type) {
-			case *ast.TypeSpec:
-				s.symlen.Accumulate(d.Name.String(), s.Fset.PositionFor(d.Pos(), true))
-				if strings.EqualFold(d.Name.String(), s.Package) {
-					fmt.Printf("type %s is identical to package %s. Make sure this is warranted.\n", d.Name.String(), s.Package)
-				} else if contains(d.Name.String(), s.Package) {
-					s.Append(d.Name.String(), s.Package, s.Fset.PositionFor(d.Pos(), true))
-				}
-			case *ast.ValueSpec:
-				for _, name := range d.Names {
-					s.symlen.Accumulate(name.String(), s.Fset.PositionFor(d.Pos(), true))
-					if contains(name.String(), s.Package) {
-						s.Append(name.String(), s.Package, s.Fset.PositionFor(d.Pos(), true))
-					}
-				}
-			}
+// reportFindings runs the same detection stutteranalyzer.Analyzer uses
+// for go vet/golangci-lint drivers and feeds each hit to reporter,
+// instead of main keeping its own copy of the heuristic.
+func reportFindings(fset *token.FileSet, pkgName string, files []*ast.File, reporter report.Reporter) error {
+	for _, f := range stutteranalyzer.Findings(pkgName, files) {
+		err := reporter.Finding(report.Finding{
+			Symbol:     f.Symbol,
+			Package:    f.Package,
+			Position:   fset.PositionFor(f.Pos, true),
+			Suggestion: f.Suggestion,
+			Rule:       f.Rule,
+		})
+		if err != nil {
+			return err
 		}
 	}
-	return s
+	return nil
 }
 
 func main() {
-	sem := make(chan struct{}, runtime.NumCPU()*4)
+	format := flag.String("format", "text", "output format: text, json, sarif, or checkstyle")
+	flag.Parse()
+
+	reporter, err := report.New(*format, os.Stdout)
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	symlen := Symlen{}
-	for _, p := range os.Args[1:] {
-		sem <- struct{}{}
-		p := p
-		go func() {
-			filepath.WalkDir(p, func(path string, d fs.DirEntry, e error) error {
-				if !d.IsDir() {
-					return nil
-				}
-
-				switch d.Name() {
-				case "testdata", "vendor":
-					return fs.SkipDir
-				}
-
-				fset := token.NewFileSet()
-
-				notest := func(f fs.FileInfo) bool {
-					return !strings.HasSuffix(f.Name(), "_test.go")
-				}
-
-				pkgs, err := parser.ParseDir(fset, path, notest, parser.SkipObjectResolution)
-				if err != nil {
-					log.Fatal(err)
-				}
-
-				visitors := map[string]*Visit{}
-				for _, pkg := range pkgs {
-					visitors[pkg.Name] = &Visit{Fset: fset, Package: pkg.Name, symlen: &symlen}
-					for _, file := range pkg.Files {
-						ast.Walk(visitors[pkg.Name], file)
-					}
-				}
-
-				for _, visitor := range visitors {
-					for _, s := range visitor.Stutter {
-						fmt.Printf("%s\n", s)
-					}
-				}
-				return nil
-			})
-			<-sem
-		}()
+	var reportMu sync.Mutex
+
+	walkFn := func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+
+		switch d.Name() {
+		case "testdata", "vendor":
+			return fs.SkipDir
+		}
+
+		fset := token.NewFileSet()
+
+		notest := func(f fs.FileInfo) bool {
+			return !strings.HasSuffix(f.Name(), "_test.go")
+		}
+
+		pkgs, skipped, err := safeparse.ParseDir(fset, path, notest, parser.SkipObjectResolution, safeparse.Config{})
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, s := range skipped {
+			log.Printf("%s", s)
+		}
+
+		reportMu.Lock()
+		defer reportMu.Unlock()
+
+		for _, pkg := range pkgs {
+			files := make([]*ast.File, 0, len(pkg.Files))
+			for _, file := range pkg.Files {
+				accumulateSymlen(fset, &symlen, file)
+				files = append(files, file)
+			}
+			if err := reportFindings(fset, pkg.Name, files, reporter); err != nil {
+				return err
+			}
+		}
+		return nil
 	}
 
-	for i := 0; i < cap(sem); i++ {
-		sem <- struct{}{}
+	if err := fastwalk.Walk(fastwalk.Config{}, flag.Args(), walkFn); err != nil {
+		log.Fatal(err)
 	}
 
-	log.Printf("longest symbol %q (%d) at %s", symlen.LongestName, symlen.Longest, symlen.LongestPosition)
-	log.Printf("average symbol length is %f", float64(symlen.TotalLength)/float64(symlen.NumSymbols))
+	if err := reporter.Stats(symlen.Stats()); err != nil {
+		log.Fatal(err)
+	}
+	if err := reporter.Flush(); err != nil {
+		log.Fatal(err)
+	}
 }
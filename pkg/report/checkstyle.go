@@ -0,0 +1,100 @@
+package report
+
+import (
+	"encoding/xml"
+	"io"
+	"sync"
+)
+
+// checkstyleFile groups findings by source file, since that's the unit
+// Checkstyle XML (and the CI systems that consume it, e.g. Jenkins)
+// expects.
+type checkstyleFile struct {
+	XMLName xml.Name          `xml:"file"`
+	Name    string            `xml:"name,attr"`
+	Errors  []checkstyleError `xml:"error"`
+}
+
+type checkstyleError struct {
+	Line     int    `xml:"line,attr"`
+	Column   int    `xml:"column,attr"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+// checkstyleReporter buffers findings grouped by file and writes a
+// single Checkstyle XML document on Flush. Finding is documented
+// (Reporter) as callable concurrently, so order/files are guarded by mu.
+type checkstyleReporter struct {
+	mu    sync.Mutex
+	w     io.Writer
+	order []string
+	files map[string]*checkstyleFile
+}
+
+func newCheckstyle(w io.Writer) *checkstyleReporter {
+	return &checkstyleReporter{w: w, files: map[string]*checkstyleFile{}}
+}
+
+func (c *checkstyleReporter) Finding(f Finding) error {
+	msg := "consider changing " + f.Package + "." + f.Symbol + " to " + f.Suggestion
+	if f.Rule == RulePackageIdentical {
+		msg = "type " + f.Symbol + " is identical to package " + f.Package
+	}
+	entry := checkstyleError{
+		Line:     f.Position.Line,
+		Column:   f.Position.Column,
+		Severity: "warning",
+		Message:  msg,
+		Source:   "stutter." + f.Rule,
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	file, ok := c.files[f.Position.Filename]
+	if !ok {
+		file = &checkstyleFile{Name: f.Position.Filename}
+		c.files[f.Position.Filename] = file
+		c.order = append(c.order, f.Position.Filename)
+	}
+	file.Errors = append(file.Errors, entry)
+	return nil
+}
+
+func (c *checkstyleReporter) Stats(Stats) error {
+	// Checkstyle XML has no summary-record concept; stats are dropped.
+	return nil
+}
+
+func (c *checkstyleReporter) Flush() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, err := io.WriteString(c.w, xml.Header); err != nil {
+		return err
+	}
+
+	files := make([]*checkstyleFile, 0, len(c.order))
+	for _, name := range c.order {
+		files = append(files, c.files[name])
+	}
+
+	root := struct {
+		XMLName xml.Name          `xml:"checkstyle"`
+		Version string            `xml:"version,attr"`
+		Files   []*checkstyleFile `xml:"file"`
+	}{
+		Version: "8.0",
+		Files:   files,
+	}
+
+	enc := xml.NewEncoder(c.w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(root); err != nil {
+		return err
+	}
+	_, err := io.WriteString(c.w, "\n")
+	return err
+}
@@ -0,0 +1,65 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// jsonFinding is the wire shape of a single JSON finding object.
+type jsonFinding struct {
+	Symbol     string `json:"symbol"`
+	Package    string `json:"package"`
+	Position   string `json:"position"`
+	Suggestion string `json:"suggestion,omitempty"`
+	Rule       string `json:"rule"`
+}
+
+// jsonStats is the wire shape of the trailing stats record.
+type jsonStats struct {
+	NumSymbols      int     `json:"num_symbols"`
+	TotalLength     int     `json:"total_length"`
+	AverageLength   float64 `json:"average_length"`
+	LongestName     string  `json:"longest_name"`
+	LongestLength   int     `json:"longest_length"`
+	LongestPosition string  `json:"longest_position"`
+}
+
+// jsonReporter emits one JSON object per line (findings first, then a
+// final "stats" record), so output stays streamable instead of buffering
+// the whole run into a single array.
+type jsonReporter struct {
+	enc *json.Encoder
+}
+
+func newJSON(w io.Writer) *jsonReporter {
+	return &jsonReporter{enc: json.NewEncoder(w)}
+}
+
+func (j *jsonReporter) Finding(f Finding) error {
+	return j.enc.Encode(jsonFinding{
+		Symbol:     f.Symbol,
+		Package:    f.Package,
+		Position:   f.Position.String(),
+		Suggestion: f.Suggestion,
+		Rule:       f.Rule,
+	})
+}
+
+func (j *jsonReporter) Stats(s Stats) error {
+	return j.enc.Encode(struct {
+		Type  string    `json:"type"`
+		Stats jsonStats `json:"stats"`
+	}{
+		Type: "stats",
+		Stats: jsonStats{
+			NumSymbols:      s.NumSymbols,
+			TotalLength:     s.TotalLength,
+			AverageLength:   s.AverageLength(),
+			LongestName:     s.LongestName,
+			LongestLength:   s.Longest,
+			LongestPosition: s.LongestPosition.String(),
+		},
+	})
+}
+
+func (j *jsonReporter) Flush() error { return nil }
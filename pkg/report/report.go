@@ -0,0 +1,77 @@
+// Package report defines a pluggable output format for stutter findings,
+// replacing the ad-hoc fmt.Printf/log.Printf calls that used to be
+// scattered across Stutter.String, Visit.Visit, and main. A Reporter
+// receives one Finding per stutter hit and a single Stats record at the
+// end of the run, and is responsible for rendering them in whatever wire
+// format its consumer expects.
+package report
+
+import (
+	"fmt"
+	"go/token"
+	"io"
+)
+
+// Rule names used in Finding.Rule.
+const (
+	RuleStutter          = "stutter"           // identifier repeats its package name
+	RulePackageIdentical = "package-identical" // type name is identical to its package name
+)
+
+// Finding is a single stutter hit: an identifier whose name repeats its
+// package name.
+type Finding struct {
+	Symbol     string         // the bare identifier, e.g. "Client"
+	Package    string         // the package it's declared in, e.g. "http"
+	Position   token.Position // where it's declared
+	Suggestion string         // the suggested rename, e.g. "http.Client"
+	Rule       string         // which heuristic fired, e.g. "stutter" or "package-identical"
+}
+
+// Stats is the run's symbol-length summary, previously interleaved with
+// findings on stderr via two log.Printf calls at the end of main.
+type Stats struct {
+	NumSymbols      int
+	TotalLength     int
+	LongestName     string
+	Longest         int
+	LongestPosition token.Position
+}
+
+// AverageLength returns the mean symbol name length, or 0 if no symbols
+// were recorded.
+func (s Stats) AverageLength() float64 {
+	if s.NumSymbols == 0 {
+		return 0
+	}
+	return float64(s.TotalLength) / float64(s.NumSymbols)
+}
+
+// Reporter renders findings and run statistics in a particular output
+// format. Finding may be called concurrently with itself but Stats is
+// always the last call, and Flush is called exactly once after that to
+// give buffering reporters (SARIF, checkstyle) a chance to write
+// their closing structure.
+type Reporter interface {
+	Finding(Finding) error
+	Stats(Stats) error
+	Flush() error
+}
+
+// New returns the built-in Reporter registered under name, writing to w.
+// Supported names are "text" (the original plain-text behavior), "json",
+// "sarif", and "checkstyle".
+func New(name string, w io.Writer) (Reporter, error) {
+	switch name {
+	case "", "text":
+		return newText(w), nil
+	case "json":
+		return newJSON(w), nil
+	case "sarif":
+		return newSARIF(w), nil
+	case "checkstyle":
+		return newCheckstyle(w), nil
+	default:
+		return nil, fmt.Errorf("report: unknown format %q", name)
+	}
+}
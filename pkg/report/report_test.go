@@ -0,0 +1,191 @@
+package report_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"go/token"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ayang64/stutter/pkg/report"
+)
+
+func sampleFinding() report.Finding {
+	return report.Finding{
+		Symbol:     "HTTPClient",
+		Package:    "http",
+		Position:   token.Position{Filename: "http.go", Line: 3, Column: 1},
+		Suggestion: "http.Client",
+		Rule:       report.RuleStutter,
+	}
+}
+
+func TestTextReporter(t *testing.T) {
+	var buf bytes.Buffer
+	r, err := report.New("text", &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Finding(sampleFinding()); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Stats(report.Stats{NumSymbols: 2, TotalLength: 10}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, `"http.HTTPClient"`) || !strings.Contains(out, `"http.Client"`) {
+		t.Errorf("text output missing expected rename text: %q", out)
+	}
+	if !strings.Contains(out, "average symbol length is 5") {
+		t.Errorf("text output missing stats line: %q", out)
+	}
+}
+
+func TestJSONReporter(t *testing.T) {
+	var buf bytes.Buffer
+	r, err := report.New("json", &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Finding(sampleFinding()); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Stats(report.Stats{NumSymbols: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2 (one finding, one stats): %q", len(lines), buf.String())
+	}
+
+	var finding struct {
+		Symbol     string `json:"symbol"`
+		Suggestion string `json:"suggestion"`
+		Rule       string `json:"rule"`
+	}
+	if err := json.Unmarshal([]byte(lines[0]), &finding); err != nil {
+		t.Fatal(err)
+	}
+	if finding.Symbol != "HTTPClient" || finding.Suggestion != "http.Client" || finding.Rule != report.RuleStutter {
+		t.Errorf("unexpected finding line: %+v", finding)
+	}
+}
+
+func TestSARIFReporterProducesValidDocument(t *testing.T) {
+	var buf bytes.Buffer
+	r, err := report.New("sarif", &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Finding(sampleFinding()); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Stats(report.Stats{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	var doc struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Results []struct {
+				RuleID string `json:"ruleId"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid SARIF JSON: %v\n%s", err, buf.String())
+	}
+	if doc.Version != "2.1.0" {
+		t.Errorf("version = %q, want 2.1.0", doc.Version)
+	}
+	if len(doc.Runs) != 1 || len(doc.Runs[0].Results) != 1 || doc.Runs[0].Results[0].RuleID != report.RuleStutter {
+		t.Errorf("unexpected SARIF structure: %+v", doc)
+	}
+}
+
+func TestCheckstyleReporterProducesValidXML(t *testing.T) {
+	var buf bytes.Buffer
+	r, err := report.New("checkstyle", &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Finding(sampleFinding()); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Stats(report.Stats{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	var doc struct {
+		XMLName xml.Name `xml:"checkstyle"`
+		Files   []struct {
+			Name   string `xml:"name,attr"`
+			Errors []struct {
+				Line    int    `xml:"line,attr"`
+				Message string `xml:"message,attr"`
+			} `xml:"error"`
+		} `xml:"file"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid checkstyle XML: %v\n%s", err, buf.String())
+	}
+	if len(doc.Files) != 1 || doc.Files[0].Name != "http.go" || len(doc.Files[0].Errors) != 1 {
+		t.Fatalf("unexpected checkstyle structure: %+v", doc)
+	}
+	if doc.Files[0].Errors[0].Line != 3 {
+		t.Errorf("error line = %d, want 3", doc.Files[0].Errors[0].Line)
+	}
+}
+
+func TestSARIFAndCheckstyleFindingAreConcurrencySafe(t *testing.T) {
+	for _, format := range []string{"sarif", "checkstyle"} {
+		format := format
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			r, err := report.New(format, &buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			const n = 50
+			var wg sync.WaitGroup
+			wg.Add(n)
+			for i := 0; i < n; i++ {
+				go func() {
+					defer wg.Done()
+					r.Finding(sampleFinding())
+				}()
+			}
+			wg.Wait()
+
+			if err := r.Stats(report.Stats{}); err != nil {
+				t.Fatal(err)
+			}
+			if err := r.Flush(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestNewUnknownFormat(t *testing.T) {
+	if _, err := report.New("yaml", &bytes.Buffer{}); err == nil {
+		t.Fatal("expected an error for an unknown format")
+	}
+}
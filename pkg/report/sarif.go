@@ -0,0 +1,134 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+)
+
+// sarifLog is a minimal SARIF 2.1.0 log, just enough structure for
+// GitHub code scanning to ingest stutter's findings.
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name  string      `json:"name"`
+	Rules []sarifRule `json:"rules,omitempty"`
+}
+
+type sarifRule struct {
+	ID string `json:"id"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn"`
+}
+
+// sarifReporter buffers every finding and writes a single SARIF log
+// document on Flush, since SARIF has no streaming form. Finding is
+// documented (Reporter) as callable concurrently, so rules/results are
+// guarded by mu.
+type sarifReporter struct {
+	mu      sync.Mutex
+	w       io.Writer
+	rules   map[string]bool
+	results []sarifResult
+}
+
+func newSARIF(w io.Writer) *sarifReporter {
+	return &sarifReporter{w: w, rules: map[string]bool{}}
+}
+
+func (s *sarifReporter) Finding(f Finding) error {
+	msg := f.Suggestion
+	if f.Rule == RulePackageIdentical {
+		msg = "type is identical to its package name"
+	} else {
+		msg = "consider changing " + f.Package + "." + f.Symbol + " to " + f.Suggestion
+	}
+
+	result := sarifResult{
+		RuleID:  f.Rule,
+		Message: sarifMessage{Text: msg},
+		Locations: []sarifLocation{{
+			PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: f.Position.Filename},
+				Region: sarifRegion{
+					StartLine:   f.Position.Line,
+					StartColumn: f.Position.Column,
+				},
+			},
+		}},
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rules[f.Rule] = true
+	s.results = append(s.results, result)
+	return nil
+}
+
+func (s *sarifReporter) Stats(Stats) error {
+	// SARIF has no place for a non-diagnostic summary record; stats are
+	// dropped for this format rather than shoehorned into a fake result.
+	return nil
+}
+
+func (s *sarifReporter) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rules := make([]sarifRule, 0, len(s.rules))
+	for id := range s.rules {
+		rules = append(rules, sarifRule{ID: id})
+	}
+
+	log := sarifLog{
+		Schema:  "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json",
+		Version: "2.1.0",
+		Runs: []sarifRun{{
+			Tool:    sarifTool{Driver: sarifDriver{Name: "stutter", Rules: rules}},
+			Results: s.results,
+		}},
+	}
+
+	enc := json.NewEncoder(s.w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(log)
+}
@@ -0,0 +1,35 @@
+package report
+
+import (
+	"fmt"
+	"io"
+)
+
+// text is the original plain-text Reporter: one "consider changing"
+// line per stutter finding (a "type ... is identical to package ..." line
+// for package-identical ones), and the symlen summary as two trailing
+// lines rather than interleaved with findings.
+type text struct {
+	w io.Writer
+}
+
+func newText(w io.Writer) *text {
+	return &text{w: w}
+}
+
+func (t *text) Finding(f Finding) error {
+	if f.Rule == RulePackageIdentical {
+		_, err := fmt.Fprintf(t.w, "type %s is identical to package %s. Make sure this is warranted.\n", f.Symbol, f.Package)
+		return err
+	}
+	_, err := fmt.Fprintf(t.w, "%s: consider changing %q to %q\n", f.Position, f.Package+"."+f.Symbol, f.Suggestion)
+	return err
+}
+
+func (t *text) Stats(s Stats) error {
+	_, err := fmt.Fprintf(t.w, "longest symbol %q (%d) at %s\naverage symbol length is %f\n",
+		s.LongestName, s.Longest, s.LongestPosition, s.AverageLength())
+	return err
+}
+
+func (t *text) Flush() error { return nil }
@@ -0,0 +1,251 @@
+// Package safeparse wraps go/parser with hardening suitable for running
+// stutter across untrusted, third-party code: a cap on file size, a cap
+// on bracket-nesting depth (checked with a non-recursive token scan
+// before the recursive-descent parser ever sees the file), and a
+// per-file parse timeout with panic recovery. Files that trip any of
+// these limits are reported as a SkippedFile rather than aborting the
+// whole run with log.Fatal.
+package safeparse
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Defaults used when the corresponding Config field is left at zero.
+const (
+	DefaultMaxDepth = 200
+	DefaultMaxSize  = 16 << 20 // 16 MiB
+	DefaultTimeout  = 10 * time.Second
+
+	// maxConcurrentParses bounds how many parse goroutines (see ParseFile)
+	// may be running at once, process-wide. go/parser has no cancellation
+	// point, so a goroutine that blows past its timeout keeps running
+	// rather than being killed; this semaphore is what keeps that from
+	// compounding into unbounded goroutines/memory across a large hostile
+	// tree. New ParseFile calls block acquiring a slot once it's full,
+	// which throttles the scan instead of growing it without limit.
+	maxConcurrentParses = 64
+)
+
+var parseSlots = make(chan struct{}, maxConcurrentParses)
+
+// Config bounds the resources a single file is allowed to consume while
+// being parsed.
+type Config struct {
+	MaxDepth int           // max nesting depth of (), [], {}; <= 0 uses DefaultMaxDepth
+	MaxSize  int64         // max file size in bytes; <= 0 uses DefaultMaxSize
+	Timeout  time.Duration // max time to parse one file; <= 0 uses DefaultTimeout
+}
+
+func (c Config) withDefaults() Config {
+	if c.MaxDepth <= 0 {
+		c.MaxDepth = DefaultMaxDepth
+	}
+	if c.MaxSize <= 0 {
+		c.MaxSize = DefaultMaxSize
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = DefaultTimeout
+	}
+	return c
+}
+
+// SkippedFile records why a file was not parsed, in place of the
+// log.Fatal the unhardened parser.ParseDir call used to trigger.
+type SkippedFile struct {
+	Path     string
+	Reason   string
+	Position token.Position
+}
+
+func (s SkippedFile) String() string {
+	return fmt.Sprintf("%s: skipped: %s", s.Position, s.Reason)
+}
+
+// ParseDir is a hardened drop-in replacement for parser.ParseDir: it
+// parses every file in dir matching filter, but a file that exceeds
+// conf's limits is recorded in the returned []SkippedFile instead of
+// aborting the scan.
+func ParseDir(fset *token.FileSet, dir string, filter func(fs.FileInfo) bool, mode parser.Mode, conf Config) (map[string]*ast.Package, []SkippedFile, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pkgs := map[string]*ast.Package{}
+	var skipped []SkippedFile
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".go") {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+
+		if filter != nil {
+			info, err := entry.Info()
+			if err != nil {
+				return nil, skipped, err
+			}
+			if !filter(info) {
+				continue
+			}
+		}
+
+		file, skip, err := ParseFile(fset, path, mode, conf)
+		if skip != nil {
+			skipped = append(skipped, *skip)
+			continue
+		}
+		if err != nil {
+			return nil, skipped, err
+		}
+
+		name := file.Name.Name
+		pkg, ok := pkgs[name]
+		if !ok {
+			pkg = &ast.Package{Name: name, Files: map[string]*ast.File{}}
+			pkgs[name] = pkg
+		}
+		pkg.Files[path] = file
+	}
+
+	return pkgs, skipped, nil
+}
+
+// ParseFile hardens a single call to parser.ParseFile. It enforces
+// conf.MaxSize before reading the file, conf.MaxDepth via a preliminary,
+// non-recursive token scan before handing the source to the
+// recursive-descent parser, and conf.Timeout with panic recovery around
+// the parse itself, so a hostile file can cause neither stack exhaustion
+// nor unbounded memory or CPU use.
+//
+// The timeout only bounds how long ParseFile waits for the result: since
+// go/parser can't be cancelled mid-parse, a file that trips the timeout
+// leaves its parse goroutine running in the background. The
+// process-wide parseSlots semaphore (see maxConcurrentParses) bounds how
+// many such goroutines can accumulate at once, so that cost stays
+// constant instead of growing with the number of hostile files in the
+// tree.
+//
+// Acquiring a slot is itself bounded by conf.Timeout: enough timed-out
+// files can leave all maxConcurrentParses slots permanently occupied by
+// abandoned goroutines, and without its own bound this call would then
+// block forever regardless of the caller's timeout, wedging the rest of
+// the scan. Once a slot can't be had within conf.Timeout, ParseFile
+// returns a SkippedFile instead of waiting indefinitely.
+func ParseFile(fset *token.FileSet, path string, mode parser.Mode, conf Config) (*ast.File, *SkippedFile, error) {
+	conf = conf.withDefaults()
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	if info.Size() > conf.MaxSize {
+		return nil, &SkippedFile{
+			Path:     path,
+			Reason:   fmt.Sprintf("file size %d bytes exceeds max %d", info.Size(), conf.MaxSize),
+			Position: token.Position{Filename: path},
+		}, nil
+	}
+
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if depth, pos, ok := scanDepth(fset, path, src, conf.MaxDepth); !ok {
+		return nil, &SkippedFile{
+			Path:     path,
+			Reason:   fmt.Sprintf("nesting depth %d exceeds max %d", depth, conf.MaxDepth),
+			Position: pos,
+		}, nil
+	}
+
+	type result struct {
+		file *ast.File
+		err  error
+	}
+	done := make(chan result, 1)
+	select {
+	case parseSlots <- struct{}{}:
+	case <-time.After(conf.Timeout):
+		return nil, &SkippedFile{
+			Path:     path,
+			Reason:   fmt.Sprintf("timed out after %s waiting for a free parse slot", conf.Timeout),
+			Position: token.Position{Filename: path},
+		}, nil
+	}
+
+	go func() {
+		defer func() {
+			<-parseSlots
+			if r := recover(); r != nil {
+				done <- result{nil, fmt.Errorf("panic while parsing %s: %v", path, r)}
+			}
+		}()
+		f, err := parser.ParseFile(fset, path, src, mode)
+		done <- result{f, err}
+	}()
+
+	select {
+	case res := <-done:
+		if res.err != nil {
+			return nil, &SkippedFile{
+				Path:     path,
+				Reason:   res.err.Error(),
+				Position: token.Position{Filename: path},
+			}, nil
+		}
+		return res.file, nil, nil
+	case <-time.After(conf.Timeout):
+		return nil, &SkippedFile{
+			Path:     path,
+			Reason:   fmt.Sprintf("parse exceeded timeout of %s", conf.Timeout),
+			Position: token.Position{Filename: path},
+		}, nil
+	}
+}
+
+// scanDepth runs a plain, iterative token.Scanner over src -- never the
+// recursive-descent parser -- to find the deepest nesting of (), [], and
+// {} pairs. It reports the position of the first token at which depth
+// exceeded maxDepth, or ok=true if the file never did.
+func scanDepth(fset *token.FileSet, path string, src []byte, maxDepth int) (depth int, pos token.Position, ok bool) {
+	file := fset.AddFile(path, -1, len(src))
+
+	var s scanner.Scanner
+	s.Init(file, src, nil, scanner.Mode(0))
+
+	cur := 0
+	max := 0
+	for {
+		p, tok, _ := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+		switch tok {
+		case token.LPAREN, token.LBRACK, token.LBRACE:
+			cur++
+			if cur > max {
+				max = cur
+			}
+			if cur > maxDepth {
+				return cur, fset.Position(p), false
+			}
+		case token.RPAREN, token.RBRACK, token.RBRACE:
+			if cur > 0 {
+				cur--
+			}
+		}
+	}
+	return max, token.Position{}, true
+}
@@ -0,0 +1,49 @@
+package safeparse
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestParseFileDegradesWhenSlotsAreStarved exercises the case that can't
+// be reached from outside the package: every parseSlots slot already
+// occupied by a parse that will never release it (simulating a pile of
+// timed-out goroutines go/parser can't cancel). Without its own timeout
+// on the semaphore acquire, ParseFile would block here forever.
+func TestParseFileDegradesWhenSlotsAreStarved(t *testing.T) {
+	for i := 0; i < maxConcurrentParses; i++ {
+		parseSlots <- struct{}{}
+	}
+	defer func() {
+		for i := 0; i < maxConcurrentParses; i++ {
+			<-parseSlots
+		}
+	}()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "a.go")
+	if err := os.WriteFile(path, []byte("package a\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	fset := token.NewFileSet()
+	start := time.Now()
+	_, skip, err := ParseFile(fset, path, parser.SkipObjectResolution, Config{Timeout: 50 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("ParseFile took %s, want it to give up close to its timeout", elapsed)
+	}
+	if skip == nil {
+		t.Fatal("expected a SkippedFile when no parse slot is available")
+	}
+	if !strings.Contains(skip.Reason, "parse slot") {
+		t.Errorf("skip reason = %q, want mention of a parse slot", skip.Reason)
+	}
+}
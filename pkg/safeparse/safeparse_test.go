@@ -0,0 +1,113 @@
+package safeparse_test
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ayang64/stutter/pkg/safeparse"
+)
+
+func writeFile(t *testing.T, dir, name, src string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestParseFileParsesValidFile(t *testing.T) {
+	dir := t.TempDir()
+	path := writeFile(t, dir, "a.go", "package a\n\nfunc F() {}\n")
+
+	fset := token.NewFileSet()
+	file, skip, err := safeparse.ParseFile(fset, path, parser.SkipObjectResolution, safeparse.Config{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if skip != nil {
+		t.Fatalf("unexpected skip: %+v", skip)
+	}
+	if file.Name.Name != "a" {
+		t.Fatalf("parsed package name = %q, want %q", file.Name.Name, "a")
+	}
+}
+
+func TestParseFileRejectsOversizedFile(t *testing.T) {
+	dir := t.TempDir()
+	path := writeFile(t, dir, "a.go", "package a\n\nfunc F() {}\n")
+
+	fset := token.NewFileSet()
+	_, skip, err := safeparse.ParseFile(fset, path, parser.SkipObjectResolution, safeparse.Config{MaxSize: 4})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if skip == nil {
+		t.Fatal("expected file to be skipped for exceeding MaxSize")
+	}
+	if !strings.Contains(skip.Reason, "exceeds max") {
+		t.Errorf("skip reason = %q, want mention of size limit", skip.Reason)
+	}
+}
+
+func TestParseFileRejectsExcessiveDepth(t *testing.T) {
+	dir := t.TempDir()
+
+	// A deeply nested set of parens is cheap to detect via the
+	// preliminary token scan, without ever handing it to the
+	// recursive-descent parser.
+	var b strings.Builder
+	b.WriteString("package a\n\nvar x = ")
+	for i := 0; i < 500; i++ {
+		b.WriteString("(")
+	}
+	b.WriteString("1")
+	for i := 0; i < 500; i++ {
+		b.WriteString(")")
+	}
+	b.WriteString("\n")
+	path := writeFile(t, dir, "deep.go", b.String())
+
+	fset := token.NewFileSet()
+	_, skip, err := safeparse.ParseFile(fset, path, parser.SkipObjectResolution, safeparse.Config{MaxDepth: 50})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if skip == nil {
+		t.Fatal("expected file to be skipped for exceeding MaxDepth")
+	}
+	if !strings.Contains(skip.Reason, "nesting depth") {
+		t.Errorf("skip reason = %q, want mention of nesting depth", skip.Reason)
+	}
+}
+
+func TestParseDirSkipsHardenedFilesButKeepsOthers(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "good.go", "package a\n")
+	writeFile(t, dir, "big.go", "package a\n\nfunc Big() {}\n")
+
+	fset := token.NewFileSet()
+	pkgs, skipped, err := safeparse.ParseDir(fset, dir, nil, parser.SkipObjectResolution, safeparse.Config{MaxSize: 15})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(skipped) != 1 || skipped[0].Path != filepath.Join(dir, "big.go") {
+		t.Fatalf("skipped = %+v, want exactly big.go", skipped)
+	}
+
+	pkg, ok := pkgs["a"]
+	if !ok {
+		t.Fatal("expected package \"a\" to be present")
+	}
+	if _, ok := pkg.Files[filepath.Join(dir, "good.go")]; !ok {
+		t.Errorf("expected good.go to be parsed, got files %v", pkg.Files)
+	}
+	if _, ok := pkg.Files[filepath.Join(dir, "big.go")]; ok {
+		t.Errorf("big.go should have been skipped, not parsed")
+	}
+}
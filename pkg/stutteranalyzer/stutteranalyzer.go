@@ -0,0 +1,196 @@
+// Package stutteranalyzer implements the stutter checker as a
+// golang.org/x/tools/go/analysis.Analyzer, so it can be driven by
+// go vet, golangci-lint, or any other analysis.Analyzer-based driver
+// instead of only the one-shot stutter binary at the module root.
+package stutteranalyzer
+
+import (
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"reflect"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// Severity classifies how strongly a finding should be surfaced by
+// drivers that understand severities (e.g. golangci-lint).
+type Severity string
+
+const (
+	SeverityWarning Severity = "warning"
+	SeverityError   Severity = "error"
+)
+
+const doc = `check for identifiers that stutter their package name
+
+The stutter analyzer flags top-level exported funcs, and top-level types,
+consts, and vars (exported or not), whose name repeats
+(case-insensitively) the name of the package they live in, e.g. package
+"http" declaring "HTTPClient" instead of "Client". Each such finding
+comes with a suggested fix that renames the identifier to the
+package-qualified form the caller would already write, e.g.
+"http.Client", stripping the redundant package-name substring. A type
+whose name equals the package name is reported without a fix.`
+
+var severity Severity
+
+func init() {
+	Analyzer.Flags.Init("stutter", flag.ExitOnError)
+	Analyzer.Flags.Var((*severityFlag)(&severity), "severity", "severity to report findings as (warning or error)")
+	severity = SeverityWarning
+}
+
+// Analyzer is the stutter go/analysis.Analyzer. Register it with
+// singlechecker or multichecker, or add it to a golangci-lint plugin's
+// analyzer list.
+var Analyzer = &analysis.Analyzer{
+	Name:       "stutter",
+	Doc:        doc,
+	Run:        run,
+	ResultType: reflect.TypeOf([]Finding{}),
+}
+
+type severityFlag Severity
+
+func (f *severityFlag) String() string {
+	if f == nil || *f == "" {
+		return string(SeverityWarning)
+	}
+	return string(*f)
+}
+
+func (f *severityFlag) Set(s string) error {
+	switch Severity(s) {
+	case SeverityWarning, SeverityError:
+		*f = severityFlag(s)
+		return nil
+	default:
+		return fmt.Errorf("stutter: unknown severity %q (want %q or %q)", s, SeverityWarning, SeverityError)
+	}
+}
+
+// suggest rewrites the stuttering symbol name s into the package-qualified
+// form pkg.Name that a caller would otherwise have to write out by hand,
+// stripping the first case-insensitive occurrence of pkg from s first.
+func suggest(pkg, s string) string {
+	if idx := strings.Index(strings.ToLower(s), strings.ToLower(pkg)); idx != -1 {
+		s = s[:idx] + s[idx+len(pkg):]
+	}
+	return pkg + "." + s
+}
+
+func contains(a, b string) bool {
+	return strings.Contains(strings.ToLower(a), strings.ToLower(b))
+}
+
+// Rule names used in Finding.Rule. These mirror pkg/report's Rule
+// constants of the same name; they're redeclared here rather than
+// imported so this package, which is meant to be usable from any
+// analysis.Pass-driven tool (go vet, golangci-lint, ...), doesn't pick up
+// a dependency on stutter's CLI-specific output formatting package.
+const (
+	RuleStutter          = "stutter"
+	RulePackageIdentical = "package-identical"
+)
+
+// Finding is a single stutter hit, described independently of the
+// analysis.Diagnostic/SuggestedFix shapes run() reports it as. It lets
+// callers that don't drive the scan through the analysis.Pass machinery
+// (such as the plain-text scan in the module root's main package) reuse the exact same
+// detection logic run() uses, instead of keeping a second copy that can
+// drift from it.
+type Finding struct {
+	Symbol     string
+	Package    string
+	Pos        token.Pos
+	End        token.Pos
+	Suggestion string // the suggested rename, e.g. "http.Client"; empty for RulePackageIdentical
+	Rule       string
+}
+
+// Findings walks every file in files, all belonging to the package named
+// pkgName, and returns every stutter and package-identical hit using
+// only syntactic information.
+func Findings(pkgName string, files []*ast.File) []Finding {
+	var findings []Finding
+
+	report := func(ident *ast.Ident, rule string) {
+		var suggestion string
+		if rule == RuleStutter {
+			suggestion = suggest(pkgName, ident.Name)
+		}
+		findings = append(findings, Finding{
+			Symbol:     ident.Name,
+			Package:    pkgName,
+			Pos:        ident.Pos(),
+			End:        ident.End(),
+			Suggestion: suggestion,
+			Rule:       rule,
+		})
+	}
+
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			switch d := decl.(type) {
+			case *ast.FuncDecl:
+				if d.Recv == nil && d.Name.IsExported() && contains(d.Name.Name, pkgName) {
+					report(d.Name, RuleStutter)
+				}
+			case *ast.GenDecl:
+				for _, spec := range d.Specs {
+					switch s := spec.(type) {
+					case *ast.TypeSpec:
+						if strings.EqualFold(s.Name.Name, pkgName) {
+							report(s.Name, RulePackageIdentical)
+						} else if contains(s.Name.Name, pkgName) {
+							report(s.Name, RuleStutter)
+						}
+					case *ast.ValueSpec:
+						for _, name := range s.Names {
+							if contains(name.Name, pkgName) {
+								report(name, RuleStutter)
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return findings
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	pkgName := pass.Pkg.Name()
+	findings := Findings(pkgName, pass.Files)
+
+	for _, f := range findings {
+		if f.Rule == RulePackageIdentical {
+			pass.Reportf(f.Pos, "type %s is identical to package %s. Make sure this is warranted.", f.Symbol, pkgName)
+			continue
+		}
+		pass.Report(analysis.Diagnostic{
+			Pos:      f.Pos,
+			End:      f.End,
+			Category: "stutter",
+			Message:  fmt.Sprintf("[%s] %q stutters package name %q; consider %q", severity, pkgName+"."+f.Symbol, pkgName, f.Suggestion),
+			SuggestedFixes: []analysis.SuggestedFix{
+				{
+					Message: fmt.Sprintf("rename to %s", f.Suggestion),
+					TextEdits: []analysis.TextEdit{
+						{
+							Pos:     f.Pos,
+							End:     f.End,
+							NewText: []byte(strings.TrimPrefix(f.Suggestion, pkgName+".")),
+						},
+					},
+				},
+			},
+		})
+	}
+
+	return findings, nil
+}
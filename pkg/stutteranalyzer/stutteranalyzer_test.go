@@ -0,0 +1,14 @@
+package stutteranalyzer_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/ayang64/stutter/pkg/stutteranalyzer"
+)
+
+func TestAnalyzer(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.RunWithSuggestedFixes(t, testdata, stutteranalyzer.Analyzer, "foo")
+}
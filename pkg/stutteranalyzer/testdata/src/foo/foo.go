@@ -0,0 +1,9 @@
+package foo
+
+type FooThing struct{} // want `"foo.FooThing" stutters package name "foo"; consider "foo.Thing"`
+
+func FooFunc() {} // want `"foo.FooFunc" stutters package name "foo"; consider "foo.Func"`
+
+type Foo struct{} // want `type Foo is identical to package foo`
+
+func Helper() {}
@@ -0,0 +1,306 @@
+// Package symindex builds and queries a persistent, on-disk index of every
+// declared identifier in a tree of Go source files. The index is built from
+// go/parser output alone -- no type checking is performed -- so it stays
+// cheap enough to rebuild for large monorepos, and is keyed by a hash of
+// each file's contents so unchanged files are skipped on subsequent runs.
+package symindex
+
+import (
+	"crypto/sha256"
+	"encoding/gob"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Kind classifies a declared identifier using only syntactic information.
+type Kind int
+
+const (
+	// KindUnknown covers TypeSpecs whose underlying type expression isn't
+	// a struct or interface literal (e.g. a named alias or generic type).
+	KindUnknown Kind = iota
+	KindFunc
+	KindMethod
+	KindStruct
+	KindInterface
+	KindConst
+	KindVar
+	KindField
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindFunc:
+		return "func"
+	case KindMethod:
+		return "method"
+	case KindStruct:
+		return "struct"
+	case KindInterface:
+		return "interface"
+	case KindConst:
+		return "const"
+	case KindVar:
+		return "var"
+	case KindField:
+		return "field"
+	default:
+		return "unknown"
+	}
+}
+
+// Symbol is a single declared identifier recorded in the index.
+type Symbol struct {
+	Name     string
+	Kind     Kind
+	Package  string
+	Exported bool
+	Position token.Position
+}
+
+// fileHash is a sha256 digest of a file's contents, used as the cache key.
+type fileHash [sha256.Size]byte
+
+// fileEntry is the cached, serialized state for a single source file.
+type fileEntry struct {
+	Hash    fileHash
+	Symbols []Symbol
+}
+
+// Index is a persistent symbol index keyed by file path. Zero value is a
+// usable, empty index.
+type Index struct {
+	Files map[string]fileEntry
+}
+
+// New returns an empty Index.
+func New() *Index {
+	return &Index{Files: map[string]fileEntry{}}
+}
+
+// Load reads a previously serialized Index from path. A missing file
+// yields an empty Index rather than an error, since the first run of any
+// tree has no cache yet.
+func Load(path string) (*Index, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return New(), nil
+		}
+		return nil, fmt.Errorf("symindex: open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	idx := New()
+	if err := gob.NewDecoder(f).Decode(idx); err != nil {
+		return nil, fmt.Errorf("symindex: decode %s: %w", path, err)
+	}
+	return idx, nil
+}
+
+// Save serializes the index to path, overwriting any previous contents.
+func (idx *Index) Save(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("symindex: create %s: %w", path, err)
+	}
+	defer f.Close()
+
+	if err := gob.NewEncoder(f).Encode(idx); err != nil {
+		return fmt.Errorf("symindex: encode %s: %w", path, err)
+	}
+	return nil
+}
+
+// Walk reindexes every .go file under root, except _test.go files and
+// anything under testdata, vendor, or .git directories, reusing cached entries for
+// files whose content hash hasn't changed since the index was last saved.
+// Any previously indexed file under root that Walk doesn't encounter this
+// time -- because it was deleted or renamed -- is pruned from the index,
+// so stale symbols don't accumulate forever. It returns the number of
+// files reparsed.
+func (idx *Index) Walk(root string) (int, error) {
+	fset := token.NewFileSet()
+	reparsed := 0
+	visited := map[string]bool{}
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			switch d.Name() {
+			case "testdata", "vendor", ".git":
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+		visited[path] = true
+
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("symindex: read %s: %w", path, err)
+		}
+		hash := sha256.Sum256(src)
+
+		if entry, ok := idx.Files[path]; ok && entry.Hash == hash {
+			return nil
+		}
+
+		file, err := parser.ParseFile(fset, path, src, parser.SkipObjectResolution)
+		if err != nil {
+			return fmt.Errorf("symindex: parse %s: %w", path, err)
+		}
+
+		idx.Files[path] = fileEntry{
+			Hash:    hash,
+			Symbols: symbolsOf(fset, file),
+		}
+		reparsed++
+		return nil
+	})
+	if err != nil {
+		return reparsed, err
+	}
+
+	for path := range idx.Files {
+		if visited[path] {
+			continue
+		}
+		if rel, relErr := filepath.Rel(root, path); relErr == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			delete(idx.Files, path)
+		}
+	}
+
+	return reparsed, nil
+}
+
+// symbolsOf extracts every declared identifier from file using only
+// syntactic information -- no type checking.
+func symbolsOf(fset *token.FileSet, file *ast.File) []Symbol {
+	pkg := file.Name.String()
+	var symbols []Symbol
+
+	pos := func(p token.Pos) token.Position {
+		return fset.PositionFor(p, true)
+	}
+
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			kind := KindFunc
+			if d.Recv != nil {
+				kind = KindMethod
+			}
+			symbols = append(symbols, Symbol{
+				Name:     d.Name.String(),
+				Kind:     kind,
+				Package:  pkg,
+				Exported: d.Name.IsExported(),
+				Position: pos(d.Pos()),
+			})
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					symbols = append(symbols, Symbol{
+						Name:     s.Name.String(),
+						Kind:     typeKind(s.Type),
+						Package:  pkg,
+						Exported: s.Name.IsExported(),
+						Position: pos(s.Pos()),
+					})
+					if st, ok := s.Type.(*ast.StructType); ok {
+						symbols = append(symbols, fieldsOf(fset, pkg, s.Name.String(), st)...)
+					}
+				case *ast.ValueSpec:
+					kind := KindVar
+					if d.Tok == token.CONST {
+						kind = KindConst
+					}
+					for _, name := range s.Names {
+						symbols = append(symbols, Symbol{
+							Name:     name.String(),
+							Kind:     kind,
+							Package:  pkg,
+							Exported: name.IsExported(),
+							Position: pos(name.Pos()),
+						})
+					}
+				}
+			}
+		}
+	}
+	return symbols
+}
+
+// typeKind maps a TypeSpec's underlying type expression to a Kind using
+// only its syntactic shape, falling back to KindUnknown when it isn't a
+// struct or interface literal (e.g. an alias or generic instantiation).
+func typeKind(expr ast.Expr) Kind {
+	switch expr.(type) {
+	case *ast.StructType:
+		return KindStruct
+	case *ast.InterfaceType:
+		return KindInterface
+	default:
+		return KindUnknown
+	}
+}
+
+func fieldsOf(fset *token.FileSet, pkg, owner string, st *ast.StructType) []Symbol {
+	var fields []Symbol
+	if st.Fields == nil {
+		return fields
+	}
+	for _, f := range st.Fields.List {
+		for _, name := range f.Names {
+			fields = append(fields, Symbol{
+				Name:     owner + "." + name.String(),
+				Kind:     KindField,
+				Package:  pkg,
+				Exported: name.IsExported(),
+				Position: fset.PositionFor(name.Pos(), true),
+			})
+		}
+	}
+	return fields
+}
+
+// Query returns every indexed symbol whose name contains substr
+// (case-sensitive) and whose Kind matches kindFilter, sorted by file path
+// and then by declaration order within the file. A zero-value substr
+// matches every symbol; kindFilter is ignored when it equals KindUnknown,
+// since that's also the zero value for Kind.
+func (idx *Index) Query(substr string, kindFilter Kind) []Symbol {
+	paths := make([]string, 0, len(idx.Files))
+	for path := range idx.Files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var results []Symbol
+	for _, path := range paths {
+		entry := idx.Files[path]
+		for _, sym := range entry.Symbols {
+			if substr != "" && !strings.Contains(sym.Name, substr) {
+				continue
+			}
+			if kindFilter != KindUnknown && sym.Kind != kindFilter {
+				continue
+			}
+			results = append(results, sym)
+		}
+	}
+	return results
+}
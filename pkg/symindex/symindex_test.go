@@ -0,0 +1,148 @@
+package symindex_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ayang64/stutter/pkg/symindex"
+)
+
+func writeFile(t *testing.T, dir, name, src string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+const aSrc = `package a
+
+type Thing struct {
+	Field int
+}
+
+type Iface interface {
+	Method()
+}
+
+const Answer = 42
+
+var Count int
+
+func Do() {}
+`
+
+func TestWalkIndexesSymbols(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "a.go", aSrc)
+
+	idx := symindex.New()
+	reparsed, err := idx.Walk(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reparsed != 1 {
+		t.Fatalf("reparsed = %d, want 1", reparsed)
+	}
+
+	want := map[string]symindex.Kind{
+		"Thing":       symindex.KindStruct,
+		"Thing.Field": symindex.KindField,
+		"Iface":       symindex.KindInterface,
+		"Answer":      symindex.KindConst,
+		"Count":       symindex.KindVar,
+		"Do":          symindex.KindFunc,
+	}
+
+	for name, kind := range want {
+		results := idx.Query(name, kind)
+		if len(results) != 1 {
+			t.Errorf("Query(%q, %v) = %d results, want 1", name, kind, len(results))
+			continue
+		}
+		if results[0].Name != name {
+			t.Errorf("Query(%q, %v) returned name %q", name, kind, results[0].Name)
+		}
+	}
+}
+
+func TestWalkReusesCacheForUnchangedFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "a.go", aSrc)
+
+	idx := symindex.New()
+	if _, err := idx.Walk(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	reparsed, err := idx.Walk(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reparsed != 0 {
+		t.Fatalf("second Walk reparsed = %d, want 0 (file unchanged)", reparsed)
+	}
+}
+
+func TestWalkPrunesDeletedFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "keep.go", "package a\n\nfunc Keep() {}\n")
+	gonePath := writeFile(t, dir, "gone.go", "package a\n\nfunc Gone() {}\n")
+
+	idx := symindex.New()
+	if _, err := idx.Walk(dir); err != nil {
+		t.Fatal(err)
+	}
+	if len(idx.Query("Gone", symindex.KindFunc)) != 1 {
+		t.Fatal("expected Gone to be indexed before deletion")
+	}
+
+	if err := os.Remove(gonePath); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := idx.Walk(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := idx.Query("Gone", symindex.KindFunc); len(got) != 0 {
+		t.Fatalf("Gone still indexed after deletion: %+v", got)
+	}
+	if got := idx.Query("Keep", symindex.KindFunc); len(got) != 1 {
+		t.Fatalf("Keep should still be indexed, got %+v", got)
+	}
+}
+
+func TestQueryIsDeterministic(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "z.go", "package a\n\nfunc ZFunc() {}\n")
+	writeFile(t, dir, "m.go", "package a\n\nfunc MFunc() {}\n")
+	writeFile(t, dir, "a.go", "package a\n\nfunc AFunc() {}\n")
+
+	idx := symindex.New()
+	if _, err := idx.Walk(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	first := idx.Query("Func", symindex.KindFunc)
+	for i := 0; i < 10; i++ {
+		got := idx.Query("Func", symindex.KindFunc)
+		if len(got) != len(first) {
+			t.Fatalf("run %d: got %d results, want %d", i, len(got), len(first))
+		}
+		for j := range got {
+			if got[j].Name != first[j].Name || got[j].Position.Filename != first[j].Position.Filename {
+				t.Fatalf("run %d: order differs at index %d: got %+v, want %+v", i, j, got[j], first[j])
+			}
+		}
+	}
+
+	// File paths should come back in sorted order: a.go, m.go, z.go.
+	wantOrder := []string{"AFunc", "MFunc", "ZFunc"}
+	for i, sym := range first {
+		if sym.Name != wantOrder[i] {
+			t.Errorf("result[%d] = %q, want %q", i, sym.Name, wantOrder[i])
+		}
+	}
+}